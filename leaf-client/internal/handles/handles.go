@@ -0,0 +1,72 @@
+// Package handles is a thin registry on top of runtime/cgo.Handle for
+// Rust-owned objects (sessions, uploads, ...) that the Go side needs to
+// reference by an opaque token instead of a Go pointer, since cgo forbids
+// storing Go pointers in C/Rust memory.
+package handles
+
+import (
+	"fmt"
+	"runtime/cgo"
+	"sync"
+)
+
+// Debug enables extra bookkeeping that turns a double Delete or a Value
+// call on an already-deleted handle into a panic naming the handle and the
+// kind of misuse. Without it, such misuse still panics, with runtime/cgo's
+// generic invalid-Handle message. It's off by default so normal builds
+// don't pay for per-handle liveness tracking. Only handles created while
+// Debug is on are tracked.
+var Debug = false
+
+var (
+	mu    sync.RWMutex
+	alive = map[uintptr]struct{}{}
+)
+
+// New registers v and returns an opaque handle for it, suitable for
+// passing across the FFI boundary as a uintptr_t.
+func New(v any) uintptr {
+	h := uintptr(cgo.NewHandle(v))
+	if Debug {
+		mu.Lock()
+		alive[h] = struct{}{}
+		mu.Unlock()
+	}
+	return h
+}
+
+// Value resolves a handle previously returned by New back to the value it
+// was registered with. Resolving a deleted handle panics; in Debug mode the
+// panic names the handle.
+func Value(h uintptr) any {
+	if Debug && !isAlive(h) {
+		panic(fmt.Sprintf("handles: use of deleted handle %d", h))
+	}
+	return cgo.Handle(h).Value()
+}
+
+// Delete releases h. Using h with Value or Delete again afterwards is a
+// caller error and panics; in Debug mode the panic names the handle.
+func Delete(h uintptr) {
+	if Debug && !takeAlive(h) {
+		panic(fmt.Sprintf("handles: double free of handle %d", h))
+	}
+	cgo.Handle(h).Delete()
+}
+
+func isAlive(h uintptr) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := alive[h]
+	return ok
+}
+
+// takeAlive reports whether h was alive, removing it from the liveness set
+// either way.
+func takeAlive(h uintptr) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	_, ok := alive[h]
+	delete(alive, h)
+	return ok
+}
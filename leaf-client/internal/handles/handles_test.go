@@ -0,0 +1,43 @@
+package handles
+
+import "testing"
+
+func TestNewValueDeleteRoundTrip(t *testing.T) {
+	h := New("payload")
+
+	if got := Value(h); got != "payload" {
+		t.Fatalf("Value(%d) = %v, want %q", h, got, "payload")
+	}
+
+	Delete(h)
+}
+
+func TestDebugDoubleFreePanics(t *testing.T) {
+	Debug = true
+	defer func() { Debug = false }()
+
+	h := New(struct{}{})
+	Delete(h)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Delete of an already-deleted handle did not panic")
+		}
+	}()
+	Delete(h)
+}
+
+func TestDebugUseAfterDeletePanics(t *testing.T) {
+	Debug = true
+	defer func() { Debug = false }()
+
+	h := New(struct{}{})
+	Delete(h)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Value of a deleted handle did not panic")
+		}
+	}()
+	Value(h)
+}
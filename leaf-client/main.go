@@ -1,19 +1,29 @@
 package main
 
-/*
-#cgo LDFLAGS: -L/home/glomonosov/projs/Leaf-Diploma-Project/target/release -lleaf -R/home/glomonosov/projs/Leaf-Diploma-Project/target/release
+import (
+	"fmt"
+	"log"
 
-#include <stdlib.h>
-#include <stdio.h>
-void hello(const char*);
-*/
-import "C"
-import "unsafe"
+	"github.com/badjokeresult/Leaf-Diploma-Project/leaf-client/pkg/leaf"
+)
 
 func main() {
-	message := "Hello from Rust"
-	cmessage := C.CString(message)
-	defer C.free(unsafe.Pointer(cmessage))
+	if err := leaf.Init(); err != nil {
+		log.Fatalf("leaf: init failed: %v", err)
+	}
+	defer leaf.Shutdown()
 
-	C.hello(cmessage)
+	chunkID := []byte("smoke-test-chunk")
+	payload := []byte("Hello from Rust")
+
+	if err := leaf.StoreChunk(chunkID, payload); err != nil {
+		log.Fatalf("leaf: store chunk failed: %v", err)
+	}
+
+	got, err := leaf.RetrieveChunk(chunkID)
+	if err != nil {
+		log.Fatalf("leaf: retrieve chunk failed: %v", err)
+	}
+
+	fmt.Printf("retrieved chunk: %s\n", got)
 }
@@ -0,0 +1,165 @@
+package leaf
+
+/*
+#include <stdint.h>
+#include <stddef.h>
+
+typedef int (*leaf_chunk_cb)(uintptr_t handle, const uint8_t* chunk_id, size_t chunk_id_len, const uint8_t* data, size_t data_len, uint64_t seq);
+
+int leaf_retrieve_stream(const uint8_t* chunk_id, size_t chunk_id_len, leaf_chunk_cb cb, uintptr_t handle);
+
+// cgo only emits _cgo_export.h declarations for consumers outside this
+// package; leafGoOnChunk's own preamble needs this forward declaration to
+// take its address as a C.leaf_chunk_cb below. The parameter types must
+// match the ones cgo itself generates for the //export'ed Go signature
+// exactly (no added const), or _cgo_export.c fails to compile with a
+// conflicting-types error.
+extern int leafGoOnChunk(uintptr_t handle, uint8_t* chunk_id, size_t chunk_id_len, uint8_t* data, size_t data_len, uint64_t seq);
+*/
+import "C"
+import (
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+)
+
+// ChunkHandler receives one chunk of a streamed retrieval at a time, in
+// order. It returns a non-zero status to abort the stream early; that
+// status is surfaced to the caller of RetrieveStream as the Code of the
+// returned *Error.
+type ChunkHandler func(chunkID, data []byte, seq uint64) int
+
+// abortStream is the non-zero status leafGoOnChunk reports to the Rust side
+// when it can't or shouldn't deliver a chunk to a handler: the handle has
+// already been released, or releaseCallback is draining it. Returning 0 (the
+// "continue" status) here would tell Rust to keep streaming through a
+// Shutdown, racing leaf_shutdown against a still-live leaf_retrieve_stream
+// call.
+const abortStream = 1
+
+// callbackEntry pins a ChunkHandler behind a cgo.Handle for the lifetime of
+// one streamed retrieval. Go pointers can't be stored on the Rust side, so
+// leaf_retrieve_stream is only ever given this handle's uintptr_t value,
+// which leafGoOnChunk resolves back to the entry.
+type callbackEntry struct {
+	fn ChunkHandler
+
+	mu       sync.Mutex
+	closing  bool
+	inFlight int
+	drained  chan struct{}
+}
+
+var (
+	callbacksMu sync.Mutex
+	callbacks   = map[cgo.Handle]*callbackEntry{}
+)
+
+func registerCallback(fn ChunkHandler) cgo.Handle {
+	entry := &callbackEntry{fn: fn, drained: make(chan struct{})}
+	h := cgo.NewHandle(entry)
+
+	callbacksMu.Lock()
+	callbacks[h] = entry
+	callbacksMu.Unlock()
+
+	return h
+}
+
+// releaseCallback marks h as closing, waits for any in-flight invocation of
+// its handler to finish, then deletes it. After this returns, the handler
+// is guaranteed never to be invoked again.
+func releaseCallback(h cgo.Handle) {
+	callbacksMu.Lock()
+	entry, ok := callbacks[h]
+	delete(callbacks, h)
+	callbacksMu.Unlock()
+	if !ok {
+		return
+	}
+	drainEntry(entry)
+	h.Delete()
+}
+
+// drainEntry marks entry as closing and blocks until its refcount of
+// in-flight callback invocations reaches zero.
+func drainEntry(entry *callbackEntry) {
+	entry.mu.Lock()
+	entry.closing = true
+	inFlight := entry.inFlight
+	entry.mu.Unlock()
+	if inFlight > 0 {
+		<-entry.drained
+	}
+}
+
+// drainCallbacks closes out every still-registered stream callback. Shutdown
+// calls this before tearing down the Rust runtime so that a retrieval still
+// in flight on another goroutine can never have its handler invoked after
+// Shutdown returns.
+func drainCallbacks() {
+	callbacksMu.Lock()
+	entries := make([]*callbackEntry, 0, len(callbacks))
+	for _, entry := range callbacks {
+		entries = append(entries, entry)
+	}
+	callbacksMu.Unlock()
+
+	for _, entry := range entries {
+		drainEntry(entry)
+	}
+}
+
+// RetrieveStream streams the chunk stored under chunkID to fn one write at a
+// time, instead of buffering the whole payload into memory the way
+// RetrieveChunk does. It blocks until the stream completes, fn aborts it,
+// or the underlying call fails.
+func RetrieveStream(chunkID []byte, fn ChunkHandler) error {
+	idBuf := newCBytes(chunkID)
+	defer idBuf.free()
+
+	h := registerCallback(fn)
+	defer releaseCallback(h)
+
+	rc := C.leaf_retrieve_stream(
+		(*C.uint8_t)(idBuf.ptr), C.size_t(idBuf.len),
+		C.leaf_chunk_cb(C.leafGoOnChunk),
+		C.uintptr_t(h),
+	)
+	if rc != 0 {
+		return lastError(rc)
+	}
+	return nil
+}
+
+//export leafGoOnChunk
+func leafGoOnChunk(handle C.uintptr_t, chunkID *C.uint8_t, chunkIDLen C.size_t, data *C.uint8_t, dataLen C.size_t, seq C.uint64_t) C.int {
+	callbacksMu.Lock()
+	entry, ok := callbacks[cgo.Handle(handle)]
+	callbacksMu.Unlock()
+	if !ok {
+		return abortStream
+	}
+
+	entry.mu.Lock()
+	if entry.closing {
+		entry.mu.Unlock()
+		return abortStream
+	}
+	entry.inFlight++
+	entry.mu.Unlock()
+
+	defer func() {
+		entry.mu.Lock()
+		entry.inFlight--
+		done := entry.closing && entry.inFlight == 0
+		entry.mu.Unlock()
+		if done {
+			close(entry.drained)
+		}
+	}()
+
+	id := C.GoBytes(unsafe.Pointer(chunkID), C.int(chunkIDLen))
+	buf := C.GoBytes(unsafe.Pointer(data), C.int(dataLen))
+	return C.int(entry.fn(id, buf, uint64(seq)))
+}
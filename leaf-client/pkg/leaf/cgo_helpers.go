@@ -0,0 +1,43 @@
+package leaf
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// cBytes is an owned C buffer backing a Go byte slice passed across the FFI
+// boundary. Callers obtain one with newCBytes and must call free exactly
+// once; a finalizer is registered as a safety net so a forgotten free does
+// not leak the underlying C allocation, but it must not be relied upon for
+// timely cleanup.
+type cBytes struct {
+	ptr unsafe.Pointer
+	len C.int
+}
+
+// newCBytes copies data into a freshly C-allocated buffer. The returned
+// cBytes owns that memory until free is called. Empty data allocates
+// nothing: the returned cBytes has a nil ptr and zero len.
+func newCBytes(data []byte) *cBytes {
+	if len(data) == 0 {
+		return &cBytes{}
+	}
+	ptr := C.CBytes(data)
+	cb := &cBytes{ptr: ptr, len: C.int(len(data))}
+	runtime.SetFinalizer(cb, (*cBytes).free)
+	return cb
+}
+
+func (cb *cBytes) free() {
+	if cb.ptr == nil {
+		return
+	}
+	C.free(cb.ptr)
+	cb.ptr = nil
+	runtime.SetFinalizer(cb, nil)
+}
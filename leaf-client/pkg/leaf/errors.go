@@ -0,0 +1,30 @@
+package leaf
+
+/*
+#include <stdlib.h>
+const char* leaf_last_error(void);
+*/
+import "C"
+
+// Error reports a failure surfaced by the Rust leaf library. Code is the
+// raw integer status returned by the failing FFI call; Message is whatever
+// leaf_last_error() had to say about it at the time, or "leaf: unknown
+// error" when it returned nothing.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// lastError turns a non-zero FFI return code into a *Error, fetching the
+// accompanying description via leaf_last_error. It never returns nil.
+func lastError(code C.int) *Error {
+	msg := C.GoString(C.leaf_last_error())
+	if msg == "" {
+		msg = "leaf: unknown error"
+	}
+	return &Error{Code: int(code), Message: msg}
+}
@@ -0,0 +1,74 @@
+// Package leaf is a thin, idiomatic Go wrapper around the Rust "leaf"
+// storage library. It exposes the library's extern "C" ABI as ordinary
+// (T, error) functions and hides all cgo bookkeeping (allocation,
+// finalizers, error translation) from callers.
+package leaf
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+
+int leaf_init(void);
+void leaf_shutdown(void);
+int leaf_store_chunk(const uint8_t* chunk_id, size_t chunk_id_len, const uint8_t* data, size_t data_len);
+int leaf_retrieve_chunk(const uint8_t* chunk_id, size_t chunk_id_len, uint8_t** out_data, size_t* out_len);
+void leaf_free_buffer(uint8_t* data, size_t len);
+*/
+import "C"
+import "unsafe"
+
+// Init starts up the Rust runtime backing this package. It must be called
+// once before any other function, and Shutdown must be called to release
+// the resources it acquires.
+func Init() error {
+	if rc := C.leaf_init(); rc != 0 {
+		return lastError(rc)
+	}
+	return nil
+}
+
+// Shutdown tears down the Rust runtime started by Init. It first drains
+// every in-flight stream callback (see RetrieveStream) so that none can
+// fire after Shutdown returns. Calling it more than once, or without a
+// prior Init, is a caller error.
+func Shutdown() {
+	drainCallbacks()
+	C.leaf_shutdown()
+}
+
+// StoreChunk writes data under chunkID, overwriting any existing chunk
+// with the same id.
+func StoreChunk(chunkID, data []byte) error {
+	idBuf := newCBytes(chunkID)
+	defer idBuf.free()
+	dataBuf := newCBytes(data)
+	defer dataBuf.free()
+
+	rc := C.leaf_store_chunk(
+		(*C.uint8_t)(idBuf.ptr), C.size_t(idBuf.len),
+		(*C.uint8_t)(dataBuf.ptr), C.size_t(dataBuf.len),
+	)
+	if rc != 0 {
+		return lastError(rc)
+	}
+	return nil
+}
+
+// RetrieveChunk reads back the chunk previously stored under chunkID.
+func RetrieveChunk(chunkID []byte) ([]byte, error) {
+	idBuf := newCBytes(chunkID)
+	defer idBuf.free()
+
+	var outData *C.uint8_t
+	var outLen C.size_t
+	rc := C.leaf_retrieve_chunk(
+		(*C.uint8_t)(idBuf.ptr), C.size_t(idBuf.len),
+		&outData, &outLen,
+	)
+	if rc != 0 {
+		return nil, lastError(rc)
+	}
+	defer C.leaf_free_buffer(outData, outLen)
+
+	return C.GoBytes(unsafe.Pointer(outData), C.int(outLen)), nil
+}
@@ -0,0 +1,7 @@
+//go:build linux
+
+package leaf
+
+// #cgo !leaf_local pkg-config: leaf
+// #cgo leaf_local LDFLAGS: -L${SRCDIR}/../../../target/release -lleaf -Wl,-rpath,${SRCDIR}/../../../target/release
+import "C"
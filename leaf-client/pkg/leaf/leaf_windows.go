@@ -0,0 +1,7 @@
+//go:build windows
+
+package leaf
+
+// #cgo !leaf_local pkg-config: leaf
+// #cgo leaf_local LDFLAGS: -L${SRCDIR}/../../../target/release -lleaf
+import "C"
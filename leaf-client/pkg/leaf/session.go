@@ -0,0 +1,75 @@
+package leaf
+
+/*
+#include <stdint.h>
+#include <stddef.h>
+
+int leaf_session_open(uintptr_t self, const uint8_t* node_id, size_t node_id_len);
+int leaf_session_close(uintptr_t self);
+*/
+import "C"
+import (
+	"log"
+	"runtime"
+
+	"github.com/badjokeresult/Leaf-Diploma-Project/leaf-client/internal/handles"
+)
+
+// Session is a Go-side reference to a Rust-owned session with a storage
+// node. Rust can't mint a cgo.Handle itself, so OpenSession allocates the
+// handle on this side and passes it into leaf_session_open as the token
+// every later Rust call on this session takes as its first argument.
+type Session struct {
+	handle C.uintptr_t
+	closed bool
+}
+
+// sessionToken is the value registered behind a Session's handle. It carries
+// no state of its own: the handle only needs to exist as an opaque token for
+// Rust, and nothing ever resolves it back with handles.Value. Registering
+// this instead of the *Session itself keeps the handle's strong reference
+// from holding the Session reachable, so its finalizer can still fire if
+// Close is never called.
+type sessionToken struct{}
+
+// OpenSession opens a session with the storage node identified by nodeID.
+// The returned Session must eventually be released with Close.
+func OpenSession(nodeID []byte) (*Session, error) {
+	s := &Session{}
+	s.handle = C.uintptr_t(handles.New(&sessionToken{}))
+
+	idBuf := newCBytes(nodeID)
+	defer idBuf.free()
+
+	if rc := C.leaf_session_open(s.handle, (*C.uint8_t)(idBuf.ptr), C.size_t(idBuf.len)); rc != 0 {
+		handles.Delete(uintptr(s.handle))
+		return nil, lastError(rc)
+	}
+
+	runtime.SetFinalizer(s, (*Session).finalize)
+	return s, nil
+}
+
+// Close releases the Rust-owned session. It is safe to call more than
+// once.
+func (s *Session) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	runtime.SetFinalizer(s, nil)
+
+	rc := C.leaf_session_close(s.handle)
+	handles.Delete(uintptr(s.handle))
+	if rc != 0 {
+		return lastError(rc)
+	}
+	return nil
+}
+
+// finalize mirrors os.File's finalizer-based safety net: a Session that
+// was never Closed is still released, but it's a bug, so we say so.
+func (s *Session) finalize() {
+	log.Printf("leaf: Session garbage collected without a call to Close")
+	_ = s.Close()
+}
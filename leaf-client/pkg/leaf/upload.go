@@ -0,0 +1,83 @@
+package leaf
+
+/*
+#include <stdint.h>
+#include <stddef.h>
+
+int leaf_upload_open(uintptr_t self, uintptr_t session, const uint8_t* chunk_id, size_t chunk_id_len);
+int leaf_upload_write(uintptr_t self, const uint8_t* data, size_t data_len);
+int leaf_upload_close(uintptr_t self);
+*/
+import "C"
+import (
+	"log"
+	"runtime"
+
+	"github.com/badjokeresult/Leaf-Diploma-Project/leaf-client/internal/handles"
+)
+
+// Upload is a Go-side reference to a Rust-owned in-flight upload of a
+// single chunk over an open Session. Like Session, its handle is minted
+// on the Go side and threaded through every Rust call.
+type Upload struct {
+	handle C.uintptr_t
+	closed bool
+}
+
+// uploadToken is the value registered behind an Upload's handle, mirroring
+// sessionToken: it exists only so the handle doesn't hold a strong
+// reference to the *Upload itself, which would keep its finalizer from
+// ever running.
+type uploadToken struct{}
+
+// OpenUpload starts uploading a chunk identified by chunkID over sess.
+// The returned Upload must eventually be released with Close.
+func (sess *Session) OpenUpload(chunkID []byte) (*Upload, error) {
+	u := &Upload{}
+	u.handle = C.uintptr_t(handles.New(&uploadToken{}))
+
+	idBuf := newCBytes(chunkID)
+	defer idBuf.free()
+
+	if rc := C.leaf_upload_open(u.handle, sess.handle, (*C.uint8_t)(idBuf.ptr), C.size_t(idBuf.len)); rc != 0 {
+		handles.Delete(uintptr(u.handle))
+		return nil, lastError(rc)
+	}
+
+	runtime.SetFinalizer(u, (*Upload).finalize)
+	return u, nil
+}
+
+// Write sends the next slice of chunk data to the Rust side without
+// buffering the whole chunk in Go.
+func (u *Upload) Write(data []byte) error {
+	dataBuf := newCBytes(data)
+	defer dataBuf.free()
+
+	if rc := C.leaf_upload_write(u.handle, (*C.uint8_t)(dataBuf.ptr), C.size_t(dataBuf.len)); rc != 0 {
+		return lastError(rc)
+	}
+	return nil
+}
+
+// Close finalizes the upload and releases its Rust-owned resources. It is
+// safe to call more than once.
+func (u *Upload) Close() error {
+	if u.closed {
+		return nil
+	}
+	u.closed = true
+	runtime.SetFinalizer(u, nil)
+
+	rc := C.leaf_upload_close(u.handle)
+	handles.Delete(uintptr(u.handle))
+	if rc != 0 {
+		return lastError(rc)
+	}
+	return nil
+}
+
+func (u *Upload) finalize() {
+	log.Printf("leaf: Upload garbage collected without a call to Close")
+	_ = u.Close()
+}